@@ -0,0 +1,53 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+)
+
+// ManagementClient is implemented by every out-of-band management backend, e.g. Redfish and IPMI, so
+// that the rest of airshipctl can drive ephemeral and target cluster nodes without knowing which
+// protocol a given site uses to reach its BMCs.
+type ManagementClient interface {
+	// NodeID retrieves the node ID understood by the backend, e.g. a Redfish system ID.
+	NodeID() string
+
+	// RebootSystem power cycles a host by sending a shutdown signal followed by a power on signal.
+	RebootSystem(ctx context.Context) error
+
+	// SetBootSourceByType sets the boot source of the node to one that's compatible with the
+	// attached boot media.
+	SetBootSourceByType(ctx context.Context) error
+
+	// SetVirtualMedia injects a virtual media device so the node boots from isoPath.
+	SetVirtualMedia(ctx context.Context, isoPath string) error
+
+	// EjectVirtualMedia ejects any virtual media devices attached to the host.
+	EjectVirtualMedia(ctx context.Context) error
+
+	// SystemPowerOff shuts down a host.
+	SystemPowerOff(ctx context.Context) error
+
+	// SystemPowerOn powers on a host.
+	SystemPowerOn(ctx context.Context) error
+
+	// SystemPowerStatus retrieves the power status of a host.
+	SystemPowerStatus(ctx context.Context) (power.Status, error)
+
+	// Close releases any resources held by the client, e.g. logging out of a Redfish session. It
+	// is a no-op for backends that don't hold any such resources.
+	Close(ctx context.Context) error
+}
@@ -0,0 +1,64 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+)
+
+// ManagementConfiguration bundles the fields required to construct a ManagementClient for a node's
+// out-of-band management endpoint, regardless of which backend ultimately services the connection.
+type ManagementConfiguration struct {
+	// ManagementType selects the backend, e.g. redfish.ClientType or ipmi.ClientType, and is
+	// normally read from the management endpoint scheme in the site config.
+	ManagementType string
+
+	URL      string
+	Insecure bool
+	UseProxy bool
+	AuthMode redfish.AuthMode
+	Username string
+	Password string
+
+	// BootType selects the one-time boot media SetBootSourceByType uses on the IPMI backend, one
+	// of "pxe", "disk", "cd", or "dvd". It is ignored by the Redfish backend, which determines the
+	// boot source from the attached virtual media instead. An empty BootType defaults to "pxe".
+	BootType string
+}
+
+// NewManagementClient selects an out-of-band management backend based on cfg.ManagementType and
+// returns a ManagementClient for it, along with the context the backend's requests should be made
+// with, e.g. one carrying Redfish basic auth credentials.
+func NewManagementClient(cfg ManagementConfiguration) (context.Context, ManagementClient, error) {
+	switch cfg.ManagementType {
+	case redfish.ClientType, "":
+		ctx, client, err := redfish.NewClient(
+			cfg.URL, cfg.Insecure, cfg.UseProxy, cfg.AuthMode, cfg.Username, cfg.Password,
+		)
+		if err != nil {
+			return ctx, nil, err
+		}
+		return ctx, client, nil
+	case ipmi.ClientType:
+		client, err := ipmi.NewClient(cfg.URL, cfg.BootType, cfg.Username, cfg.Password)
+		if err != nil {
+			return context.Background(), nil, err
+		}
+		return context.Background(), client, nil
+	default:
+		return context.Background(), nil, ErrUnknownManagementType{ManagementType: cfg.ManagementType}
+	}
+}
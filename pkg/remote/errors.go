@@ -0,0 +1,45 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownManagementType is returned by NewManagementClient when a site's management endpoint
+// names a ClientType that has no registered backend.
+type ErrUnknownManagementType struct {
+	ManagementType string
+}
+
+func (e ErrUnknownManagementType) Error() string {
+	return fmt.Sprintf("unknown out-of-band management type '%s'", e.ManagementType)
+}
+
+// ErrFleetOperation aggregates the per-node errors from a Fleet-wide operation, keyed by node ID.
+// Nodes that succeeded are omitted.
+type ErrFleetOperation struct {
+	Failures map[string]error
+}
+
+func (e ErrFleetOperation) Error() string {
+	messages := make([]string, 0, len(e.Failures))
+	for nodeID, err := range e.Failures {
+		messages = append(messages, fmt.Sprintf("%s: %v", nodeID, err))
+	}
+	sort.Strings(messages)
+
+	return fmt.Sprintf("fleet operation failed on %d node(s): %s", len(e.Failures), strings.Join(messages, "; "))
+}
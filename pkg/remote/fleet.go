@@ -0,0 +1,210 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/retry"
+)
+
+const (
+	// DefaultFleetWorkers is the worker pool size a Fleet uses when none is given.
+	DefaultFleetWorkers = 10
+
+	fleetBackoffBase = 2 * time.Second
+	fleetBackoffCap  = 30 * time.Second
+	fleetOpRetries   = 5
+)
+
+// Fleet drives the same out-of-band management operation across many nodes concurrently, bounding
+// parallelism to a worker pool and retrying transient per-node failures with exponential backoff so
+// that bringing up dozens of nodes doesn't serialize on one sleep per node.
+type Fleet struct {
+	// Contexts holds the context each Nodes entry's requests should be made with, e.g. one
+	// carrying Redfish basic auth credentials. A node with no entry, or a nil entry, uses the
+	// context passed to the Fleet operation instead.
+	Contexts []context.Context
+	Nodes    []ManagementClient
+
+	// Workers bounds how many node operations run concurrently. It defaults to
+	// DefaultFleetWorkers when less than one.
+	Workers int
+}
+
+// NewFleet returns a Fleet that drives nodes concurrently, bounded by workers.
+func NewFleet(workers int, contexts []context.Context, nodes []ManagementClient) *Fleet {
+	return &Fleet{Contexts: contexts, Nodes: nodes, Workers: workers}
+}
+
+// RebootAll reboots every node in the fleet concurrently.
+func (f *Fleet) RebootAll(ctx context.Context) error {
+	return f.forEach(ctx, func(ctx context.Context, node ManagementClient) error {
+		return node.RebootSystem(ctx)
+	})
+}
+
+// SetVirtualMediaAll attaches isoPath as virtual media on every node in the fleet concurrently.
+func (f *Fleet) SetVirtualMediaAll(ctx context.Context, isoPath string) error {
+	return f.forEach(ctx, func(ctx context.Context, node ManagementClient) error {
+		return node.SetVirtualMedia(ctx, isoPath)
+	})
+}
+
+// PowerOffAll powers off every node in the fleet concurrently.
+func (f *Fleet) PowerOffAll(ctx context.Context) error {
+	return f.forEach(ctx, func(ctx context.Context, node ManagementClient) error {
+		return node.SystemPowerOff(ctx)
+	})
+}
+
+// PowerOnAll powers on every node in the fleet concurrently.
+func (f *Fleet) PowerOnAll(ctx context.Context) error {
+	return f.forEach(ctx, func(ctx context.Context, node ManagementClient) error {
+		return node.SystemPowerOn(ctx)
+	})
+}
+
+// CloseAll releases every node's client resources, e.g. logging out of Redfish sessions. Callers
+// that construct a Fleet should defer CloseAll once they're done driving it.
+func (f *Fleet) CloseAll(ctx context.Context) error {
+	return f.forEach(ctx, func(ctx context.Context, node ManagementClient) error {
+		return node.Close(ctx)
+	})
+}
+
+// forEach runs op against every node in the fleet, bounded by f.Workers concurrent operations, and
+// aggregates any per-node failures into a single ErrFleetOperation.
+func (f *Fleet) forEach(ctx context.Context, op func(context.Context, ManagementClient) error) error {
+	workers := f.Workers
+	if workers < 1 {
+		workers = DefaultFleetWorkers
+	}
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	errs := make([]error, len(f.Nodes))
+
+	for i, node := range f.Nodes {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, node ManagementClient) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			errs[i] = f.withRetry(ctx, i, node, op)
+		}(i, node)
+	}
+
+	wg.Wait()
+
+	failures := make(map[string]error)
+	for i, err := range errs {
+		if err != nil {
+			failures[f.Nodes[i].NodeID()] = err
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+	return ErrFleetOperation{Failures: failures}
+}
+
+// withRetry runs op against node, retrying transient HTTP 5xx/timeout failures with exponential
+// backoff and jitter, honoring ctx.Done() between attempts.
+func (f *Fleet) withRetry(
+	ctx context.Context,
+	i int,
+	node ManagementClient,
+	op func(context.Context, ManagementClient) error,
+) error {
+	nodeCtx := ctx
+	if i < len(f.Contexts) && f.Contexts[i] != nil {
+		nodeCtx = withValuesFrom(ctx, f.Contexts[i])
+	}
+
+	backoff := retry.NewBackoff(fleetBackoffBase, fleetBackoffCap)
+
+	var err error
+	for attempt := 0; attempt < fleetOpRetries; attempt++ {
+		err = op(nodeCtx, node)
+		if err == nil || !isTransient(err) {
+			return err
+		}
+
+		log.Debugf("Transient error on node '%s', retrying: %v", node.NodeID(), err)
+		if waitErr := backoff.Wait(ctx); waitErr != nil {
+			return waitErr
+		}
+	}
+
+	return err
+}
+
+// valuesContext is a context.Context whose cancellation and deadline come from cancel while its
+// values come from values, e.g. the per-node auth credentials attached to a Fleet's Contexts entry.
+// This lets withRetry honor the caller's ctx.Done() without discarding the per-node context it's
+// merging it with.
+type valuesContext struct {
+	context.Context
+	values context.Context
+}
+
+// withValuesFrom returns a context whose cancellation and deadline come from cancel but whose
+// Value lookups fall through to values, so canceling cancel is observed by node operations running
+// on the returned context without losing values's per-node auth credentials.
+func withValuesFrom(cancel, values context.Context) context.Context {
+	return valuesContext{Context: cancel, values: values}
+}
+
+func (c valuesContext) Value(key interface{}) interface{} {
+	return c.values.Value(key)
+}
+
+// transientStatusError is implemented by errors that carry the HTTP status code of the failed
+// request, e.g. the errors ScreenRedfishError produces from a non-2xx response.
+type transientStatusError interface {
+	StatusCode() int
+}
+
+// isTransient reports whether err represents a failure worth retrying, namely a context deadline, a
+// network timeout, or an HTTP 5xx response.
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+
+	var statusErr transientStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode() >= http.StatusInternalServerError
+	}
+
+	return false
+}
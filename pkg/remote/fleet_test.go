@@ -0,0 +1,188 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+)
+
+// fakeStatusError is a fake error type implementing transientStatusError, standing in for the
+// errors ScreenRedfishError produces from a non-2xx Redfish response.
+type fakeStatusError struct {
+	code int
+}
+
+func (e fakeStatusError) Error() string {
+	return "fake status error"
+}
+
+func (e fakeStatusError) StatusCode() int {
+	return e.code
+}
+
+// fakeTimeoutError is a fake net.Error, standing in for the timeout errors the Redfish HTTP client
+// can return.
+type fakeTimeoutError struct{}
+
+func (e fakeTimeoutError) Error() string   { return "fake timeout error" }
+func (e fakeTimeoutError) Timeout() bool   { return true }
+func (e fakeTimeoutError) Temporary() bool { return true }
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "context deadline exceeded", err: context.DeadlineExceeded, want: true},
+		{
+			name: "wrapped context deadline exceeded",
+			err:  fmt.Errorf("polling node: %w", context.DeadlineExceeded),
+			want: true,
+		},
+		{name: "net timeout", err: fakeTimeoutError{}, want: true},
+		{name: "http 500", err: fakeStatusError{code: http.StatusInternalServerError}, want: true},
+		{name: "http 503", err: fakeStatusError{code: http.StatusServiceUnavailable}, want: true},
+		{name: "http 404", err: fakeStatusError{code: http.StatusNotFound}, want: false},
+		{name: "plain error", err: errors.New("boom"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isTransient(tt.err))
+		})
+	}
+}
+
+// fakeManagementClient is a ManagementClient whose RebootSystem is driven by a test-supplied
+// reboot func, so tests can simulate transient failures, permanent failures, or concurrency
+// without a real out-of-band backend.
+type fakeManagementClient struct {
+	nodeID string
+	reboot func() error
+}
+
+func (c *fakeManagementClient) NodeID() string                                { return c.nodeID }
+func (c *fakeManagementClient) RebootSystem(ctx context.Context) error        { return c.reboot() }
+func (c *fakeManagementClient) SetBootSourceByType(ctx context.Context) error { return nil }
+func (c *fakeManagementClient) SetVirtualMedia(ctx context.Context, isoPath string) error {
+	return nil
+}
+func (c *fakeManagementClient) EjectVirtualMedia(ctx context.Context) error { return nil }
+func (c *fakeManagementClient) SystemPowerOff(ctx context.Context) error    { return nil }
+func (c *fakeManagementClient) SystemPowerOn(ctx context.Context) error     { return nil }
+func (c *fakeManagementClient) SystemPowerStatus(ctx context.Context) (power.Status, error) {
+	return power.StatusOn, nil
+}
+func (c *fakeManagementClient) Close(ctx context.Context) error { return nil }
+
+func TestFleetRebootAllRetriesTransientFailure(t *testing.T) {
+	var attempts int32
+	node := &fakeManagementClient{
+		nodeID: "node1",
+		reboot: func() error {
+			if atomic.AddInt32(&attempts, 1) < 2 {
+				return fakeStatusError{code: http.StatusServiceUnavailable}
+			}
+			return nil
+		},
+	}
+	fleet := NewFleet(1, nil, []ManagementClient{node})
+
+	err := fleet.RebootAll(context.Background())
+
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&attempts))
+}
+
+func TestFleetRebootAllDoesNotRetryPermanentFailure(t *testing.T) {
+	var attempts int32
+	permanent := errors.New("permanent failure")
+	node := &fakeManagementClient{
+		nodeID: "node1",
+		reboot: func() error {
+			atomic.AddInt32(&attempts, 1)
+			return permanent
+		},
+	}
+	fleet := NewFleet(1, nil, []ManagementClient{node})
+
+	err := fleet.RebootAll(context.Background())
+
+	require.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&attempts))
+}
+
+func TestFleetRebootAllAggregatesFailures(t *testing.T) {
+	failingErr := errors.New("reboot failed")
+	nodes := []ManagementClient{
+		&fakeManagementClient{nodeID: "good", reboot: func() error { return nil }},
+		&fakeManagementClient{nodeID: "bad", reboot: func() error { return failingErr }},
+	}
+	fleet := NewFleet(2, nil, nodes)
+
+	err := fleet.RebootAll(context.Background())
+
+	require.Error(t, err)
+	fleetErr, ok := err.(ErrFleetOperation)
+	require.True(t, ok)
+	assert.Len(t, fleetErr.Failures, 1)
+	assert.Equal(t, failingErr, fleetErr.Failures["bad"])
+}
+
+func TestFleetRebootAllHonorsWorkerLimit(t *testing.T) {
+	const workers = 2
+	var current, max int32
+	var mu sync.Mutex
+
+	nodes := make([]ManagementClient, 10)
+	for i := range nodes {
+		nodes[i] = &fakeManagementClient{
+			nodeID: fmt.Sprintf("node%d", i),
+			reboot: func() error {
+				n := atomic.AddInt32(&current, 1)
+				mu.Lock()
+				if n > max {
+					max = n
+				}
+				mu.Unlock()
+
+				// Give other goroutines a chance to start concurrently before this one
+				// finishes.
+				time.Sleep(10 * time.Millisecond)
+
+				atomic.AddInt32(&current, -1)
+				return nil
+			},
+		}
+	}
+	fleet := NewFleet(workers, nil, nodes)
+
+	err := fleet.RebootAll(context.Background())
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, int(max), workers)
+}
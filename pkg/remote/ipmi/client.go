@@ -0,0 +1,163 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/goipmi"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+)
+
+// ClientType is used by other packages as the identifier of the IPMI client.
+const ClientType string = "ipmi"
+
+const (
+	defaultPort     = 623
+	defaultBootType = "pxe"
+)
+
+// bootDeviceByMediaType maps the airshipctl virtual media type convention to the `chassis bootdev`
+// argument understood by the BMC.
+var bootDeviceByMediaType = map[string]goipmi.BootDevice{
+	"cd":   goipmi.BootDeviceRemoteCdrom,
+	"dvd":  goipmi.BootDeviceRemoteCdrom,
+	"disk": goipmi.BootDeviceDisk,
+	"pxe":  goipmi.BootDevicePxe,
+}
+
+// Client holds details about an IPMI out-of-band system required for out-of-band management. It
+// satisfies the remote.ManagementClient interface for BMCs that don't support Redfish.
+type Client struct {
+	nodeID    string
+	bootType  string
+	Transport Transport
+}
+
+// NodeID retrieves the ephemeral node ID, which for IPMI is the BMC address as passed to NewClient.
+func (c *Client) NodeID() string {
+	return c.nodeID
+}
+
+// SystemPowerOn powers on a host.
+func (c *Client) SystemPowerOn(ctx context.Context) error {
+	return c.Transport.ChassisControl(ctx, goipmi.ControlPowerUp)
+}
+
+// SystemPowerOff shuts down a host.
+func (c *Client) SystemPowerOff(ctx context.Context) error {
+	return c.Transport.ChassisControl(ctx, goipmi.ControlPowerDown)
+}
+
+// SystemPowerStatus retrieves the power status of a host as a human-readable string.
+func (c *Client) SystemPowerStatus(ctx context.Context) (power.Status, error) {
+	status, err := c.Transport.ChassisStatus(ctx)
+	if err != nil {
+		return power.StatusUnknown, err
+	}
+
+	if status.PowerIsOn {
+		return power.StatusOn, nil
+	}
+	return power.StatusOff, nil
+}
+
+// RebootSystem power cycles a host by sending a chassis power-cycle command. It returns once the
+// BMC accepts the command and does not wait for the host to be reported powered on again.
+func (c *Client) RebootSystem(ctx context.Context) error {
+	log.Debugf("Rebooting node '%s' via IPMI chassis power cycle.", c.nodeID)
+	return c.Transport.ChassisControl(ctx, goipmi.ControlPowerCycle)
+}
+
+// SetBootSourceByType sets the one-time boot device of the node via `chassis bootdev`, using the
+// client's configured media type, e.g. PXE, disk, or remote CD/DVD.
+func (c *Client) SetBootSourceByType(ctx context.Context) error {
+	device, ok := bootDeviceByMediaType[strings.ToLower(c.bootType)]
+	if !ok {
+		return ErrIPMIUnsupportedBootType{BootType: c.bootType}
+	}
+
+	log.Debugf("Setting one-time boot device to '%s'.", c.bootType)
+	return c.Transport.ChassisSetBootDevice(ctx, device)
+}
+
+// SetVirtualMedia is not supported over IPMI; attaching virtual media requires a vendor-specific
+// interface such as Redfish or a dedicated KVM/virtual media session.
+func (c *Client) SetVirtualMedia(ctx context.Context, isoPath string) error {
+	return ErrIPMIOperationUnsupported{Operation: fmt.Sprintf("set virtual media '%s'", isoPath)}
+}
+
+// EjectVirtualMedia is not supported over IPMI.
+func (c *Client) EjectVirtualMedia(ctx context.Context) error {
+	return ErrIPMIOperationUnsupported{Operation: "eject virtual media"}
+}
+
+// Close closes the underlying IPMI LAN session opened by NewClient.
+func (c *Client) Close(ctx context.Context) error {
+	return c.Transport.Close()
+}
+
+// NewClient returns a client with the capability to make IPMI requests. address is of the form
+// "host[:port]" and bootType selects the media type SetBootSourceByType boots from, one of the keys
+// of bootDeviceByMediaType ("pxe", "disk", "cd", or "dvd"); an empty bootType defaults to "pxe".
+func NewClient(address string, bootType string, username, password string) (*Client, error) {
+	if address == "" {
+		return nil, ErrIPMIMissingConfig{What: "BMC address"}
+	}
+
+	if bootType == "" {
+		bootType = defaultBootType
+	}
+
+	host, port := splitHostPort(address)
+
+	connectionProperties := &goipmi.Connection{
+		Hostname:  host,
+		Port:      port,
+		Username:  username,
+		Password:  password,
+		Interface: "lanplus",
+	}
+
+	transport, err := newLANTransport(connectionProperties)
+	if err != nil {
+		return nil, ErrIPMIClient{Message: fmt.Sprintf("failed to connect to BMC '%s': %v", address, err)}
+	}
+
+	return &Client{
+		nodeID:    address,
+		bootType:  bootType,
+		Transport: transport,
+	}, nil
+}
+
+// splitHostPort splits address, of the form "host[:port]", into a host and port suitable for
+// goipmi.Connection, falling back to defaultPort when address has no ":" suffix or the suffix
+// isn't a valid port number.
+func splitHostPort(address string) (string, int) {
+	idx := strings.LastIndex(address, ":")
+	if idx == -1 {
+		return address, defaultPort
+	}
+
+	port, err := strconv.Atoi(address[idx+1:])
+	if err != nil {
+		return address[:idx], defaultPort
+	}
+	return address[:idx], port
+}
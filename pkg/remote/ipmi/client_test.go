@@ -0,0 +1,215 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/goipmi"
+
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+)
+
+// fakeTransport is a Transport that records the last command it received and returns canned
+// responses/errors, in lieu of talking to a real BMC.
+type fakeTransport struct {
+	lastControl      goipmi.ChassisControl
+	lastBootDevice   goipmi.BootDevice
+	status           *goipmi.ChassisStatusResponse
+	controlErr       error
+	statusErr        error
+	setBootDeviceErr error
+	closeErr         error
+	closed           bool
+}
+
+func (t *fakeTransport) ChassisControl(ctx context.Context, control goipmi.ChassisControl) error {
+	t.lastControl = control
+	return t.controlErr
+}
+
+func (t *fakeTransport) ChassisStatus(ctx context.Context) (*goipmi.ChassisStatusResponse, error) {
+	return t.status, t.statusErr
+}
+
+func (t *fakeTransport) ChassisSetBootDevice(ctx context.Context, device goipmi.BootDevice) error {
+	t.lastBootDevice = device
+	return t.setBootDeviceErr
+}
+
+func (t *fakeTransport) Close() error {
+	t.closed = true
+	return t.closeErr
+}
+
+func TestClientSystemPowerOn(t *testing.T) {
+	transport := &fakeTransport{}
+	client := &Client{nodeID: "node1", Transport: transport}
+
+	err := client.SystemPowerOn(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, goipmi.ControlPowerUp, transport.lastControl)
+}
+
+func TestClientSystemPowerOff(t *testing.T) {
+	transport := &fakeTransport{}
+	client := &Client{nodeID: "node1", Transport: transport}
+
+	err := client.SystemPowerOff(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, goipmi.ControlPowerDown, transport.lastControl)
+}
+
+func TestClientRebootSystem(t *testing.T) {
+	transport := &fakeTransport{}
+	client := &Client{nodeID: "node1", Transport: transport}
+
+	err := client.RebootSystem(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, goipmi.ControlPowerCycle, transport.lastControl)
+}
+
+func TestClientSystemPowerStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *goipmi.ChassisStatusResponse
+		err    error
+		want   power.Status
+	}{
+		{
+			name:   "on",
+			status: &goipmi.ChassisStatusResponse{PowerIsOn: true},
+			want:   power.StatusOn,
+		},
+		{
+			name:   "off",
+			status: &goipmi.ChassisStatusResponse{PowerIsOn: false},
+			want:   power.StatusOff,
+		},
+		{
+			name: "error",
+			err:  errors.New("bmc unreachable"),
+			want: power.StatusUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fakeTransport{status: tt.status, statusErr: tt.err}
+			client := &Client{nodeID: "node1", Transport: transport}
+
+			got, err := client.SystemPowerStatus(context.Background())
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClientSetBootSourceByType(t *testing.T) {
+	tests := []struct {
+		name       string
+		bootType   string
+		wantDevice goipmi.BootDevice
+		wantErr    bool
+	}{
+		{name: "pxe", bootType: "pxe", wantDevice: goipmi.BootDevicePxe},
+		{name: "disk", bootType: "disk", wantDevice: goipmi.BootDeviceDisk},
+		{name: "cd", bootType: "cd", wantDevice: goipmi.BootDeviceRemoteCdrom},
+		{name: "dvd", bootType: "dvd", wantDevice: goipmi.BootDeviceRemoteCdrom},
+		{name: "case-insensitive", bootType: "PXE", wantDevice: goipmi.BootDevicePxe},
+		{name: "unsupported", bootType: "usb", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fakeTransport{}
+			client := &Client{nodeID: "node1", bootType: tt.bootType, Transport: transport}
+
+			err := client.SetBootSourceByType(context.Background())
+
+			if tt.wantErr {
+				assert.IsType(t, ErrIPMIUnsupportedBootType{}, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDevice, transport.lastBootDevice)
+		})
+	}
+}
+
+func TestClientSetVirtualMediaUnsupported(t *testing.T) {
+	client := &Client{nodeID: "node1", Transport: &fakeTransport{}}
+
+	err := client.SetVirtualMedia(context.Background(), "http://example.com/image.iso")
+
+	assert.IsType(t, ErrIPMIOperationUnsupported{}, err)
+}
+
+func TestClientEjectVirtualMediaUnsupported(t *testing.T) {
+	client := &Client{nodeID: "node1", Transport: &fakeTransport{}}
+
+	err := client.EjectVirtualMedia(context.Background())
+
+	assert.IsType(t, ErrIPMIOperationUnsupported{}, err)
+}
+
+func TestClientClose(t *testing.T) {
+	transport := &fakeTransport{}
+	client := &Client{nodeID: "node1", Transport: transport}
+
+	err := client.Close(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, transport.closed)
+}
+
+func TestNewClientMissingAddress(t *testing.T) {
+	_, err := NewClient("", "", "user", "pass")
+
+	assert.IsType(t, ErrIPMIMissingConfig{}, err)
+}
+
+func TestSplitHostPort(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		wantHost string
+		wantPort int
+	}{
+		{name: "host only", address: "bmc.example.com", wantHost: "bmc.example.com", wantPort: defaultPort},
+		{name: "host and port", address: "bmc.example.com:6230", wantHost: "bmc.example.com", wantPort: 6230},
+		{name: "ipv4 and port", address: "10.0.0.1:623", wantHost: "10.0.0.1", wantPort: 623},
+		{name: "non-numeric suffix", address: "bmc.example.com:lanplus", wantHost: "bmc.example.com", wantPort: defaultPort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, port := splitHostPort(tt.address)
+
+			assert.Equal(t, tt.wantHost, host)
+			assert.Equal(t, tt.wantPort, port)
+		})
+	}
+}
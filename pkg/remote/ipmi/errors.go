@@ -0,0 +1,53 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipmi
+
+import "fmt"
+
+// ErrIPMIMissingConfig is returned when a required IPMI client configuration field is missing.
+type ErrIPMIMissingConfig struct {
+	What string
+}
+
+func (e ErrIPMIMissingConfig) Error() string {
+	return fmt.Sprintf("missing IPMI client configuration: %s", e.What)
+}
+
+// ErrIPMIClient is returned for errors encountered while communicating with a BMC over IPMI.
+type ErrIPMIClient struct {
+	Message string
+}
+
+func (e ErrIPMIClient) Error() string {
+	return e.Message
+}
+
+// ErrIPMIUnsupportedBootType is returned when SetBootSourceByType is called with a media type that
+// has no IPMI chassis boot device mapping.
+type ErrIPMIUnsupportedBootType struct {
+	BootType string
+}
+
+func (e ErrIPMIUnsupportedBootType) Error() string {
+	return fmt.Sprintf("no IPMI boot device mapping for boot type '%s'", e.BootType)
+}
+
+// ErrIPMIOperationUnsupported is returned for ManagementClient operations that have no IPMI
+// equivalent, e.g. virtual media.
+type ErrIPMIOperationUnsupported struct {
+	Operation string
+}
+
+func (e ErrIPMIOperationUnsupported) Error() string {
+	return fmt.Sprintf("operation not supported over IPMI: %s", e.Operation)
+}
@@ -0,0 +1,62 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipmi
+
+import (
+	"context"
+
+	"github.com/vmware/goipmi"
+)
+
+// Transport sends IPMI 2.0 chassis commands to a BMC. It is implemented by lanTransport, which wraps
+// the go-ipmi library, and by a fake transport in this package's tests.
+type Transport interface {
+	ChassisControl(ctx context.Context, control goipmi.ChassisControl) error
+	ChassisStatus(ctx context.Context) (*goipmi.ChassisStatusResponse, error)
+	ChassisSetBootDevice(ctx context.Context, device goipmi.BootDevice) error
+	Close() error
+}
+
+// lanTransport is the default Transport, sending commands over IPMI 2.0 LAN+ to a real BMC.
+type lanTransport struct {
+	client *goipmi.Client
+}
+
+func newLANTransport(connection *goipmi.Connection) (*lanTransport, error) {
+	client, err := goipmi.NewClient(connection)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.Open(); err != nil {
+		return nil, err
+	}
+
+	return &lanTransport{client: client}, nil
+}
+
+func (t *lanTransport) ChassisControl(ctx context.Context, control goipmi.ChassisControl) error {
+	return t.client.Control(control)
+}
+
+func (t *lanTransport) ChassisStatus(ctx context.Context) (*goipmi.ChassisStatusResponse, error) {
+	return t.client.GetChassisStatus()
+}
+
+func (t *lanTransport) ChassisSetBootDevice(ctx context.Context, device goipmi.BootDevice) error {
+	return t.client.SetBootDevice(device)
+}
+
+func (t *lanTransport) Close() error {
+	return t.client.Close()
+}
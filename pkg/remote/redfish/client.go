@@ -25,6 +25,7 @@ import (
 
 	"opendev.org/airship/airshipctl/pkg/log"
 	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/retry"
 )
 
 // contextKey is used by the redfish package as a unique key type in order to prevent collisions
@@ -35,8 +36,28 @@ const (
 	// ClientType is used by other packages as the identifier of the Redfish client.
 	ClientType          string     = "redfish"
 	systemActionRetries            = 30
-	systemRebootDelay              = 30 * time.Second
 	ctxKeyNumRetries    contextKey = "numRetries"
+
+	biosSettingsApplyRetries = 30
+
+	pollBackoffBase = 2 * time.Second
+	pollBackoffCap  = 30 * time.Second
+
+	headerAuthToken = "X-Auth-Token"
+)
+
+// AuthMode selects how NewClient authenticates against the Redfish service root.
+type AuthMode string
+
+const (
+	// AuthModeBasic authenticates every request with HTTP Basic auth. This is the default.
+	AuthModeBasic AuthMode = "basic"
+
+	// AuthModeSession exchanges credentials for a session token once, via the SessionService,
+	// and authenticates subsequent requests with that token. Some BMCs, notably Dell iDRAC and
+	// HPE iLO, rate-limit or lock out accounts under repeated Basic auth, so long-running
+	// workflows against many nodes should prefer session auth.
+	AuthModeSession AuthMode = "session"
 )
 
 // Client holds details about a Redfish out-of-band system required for out-of-band management.
@@ -44,6 +65,11 @@ type Client struct {
 	nodeID     string
 	RedfishAPI redfishAPI.RedfishAPI
 	RedfishCFG *redfishClient.Configuration
+
+	authMode   AuthMode
+	username   string
+	password   string
+	sessionURI string
 }
 
 // NodeID retrieves the ephemeral node ID.
@@ -51,6 +77,69 @@ func (c *Client) NodeID() string {
 	return c.nodeID
 }
 
+// Close logs out of the Redfish session opened by NewClient, if one exists. It is a no-op for
+// clients using HTTP Basic auth.
+func (c *Client) Close(ctx context.Context) error {
+	if c.authMode != AuthModeSession || c.sessionURI == "" {
+		return nil
+	}
+
+	log.Debugf("Logging out of Redfish session for node '%s'.", c.nodeID)
+	_, httpResp, err := c.RedfishAPI.DeleteSession(ctx, GetResourceIDFromURL(c.sessionURI))
+	return ScreenRedfishError(httpResp, err)
+}
+
+// doWithReauth invokes call, which should perform a single Redfish API request and return its raw
+// HTTP response. If the request fails with 401 Unauthorized on a session-authenticated client, it
+// transparently refreshes the session token, e.g. because the BMC expired the session on a
+// long-running workflow, and retries call once with the new token before giving up.
+func (c *Client) doWithReauth(ctx context.Context, call func() (*http.Response, error)) error {
+	httpResp, err := call()
+	screened := ScreenRedfishError(httpResp, err)
+	if screened == nil {
+		return nil
+	}
+
+	if c.authMode != AuthModeSession || httpResp == nil || httpResp.StatusCode != http.StatusUnauthorized {
+		return screened
+	}
+
+	log.Debugf("Redfish session for node '%s' expired; re-authenticating.", c.nodeID)
+	if sessionErr := c.createSession(ctx); sessionErr != nil {
+		return ErrRedfishClient{
+			Message: fmt.Sprintf("session re-authentication for node '%s' failed: %v", c.nodeID, sessionErr),
+		}
+	}
+
+	httpResp, err = call()
+	return ScreenRedfishError(httpResp, err)
+}
+
+// createSession exchanges the client's username and password for a session token via the Redfish
+// SessionService, storing the token in the client's default headers and the session URI so that
+// Close can log out of it later.
+func (c *Client) createSession(ctx context.Context) error {
+	sessionReq := redfishClient.SessionCreateRequestBody{
+		UserName: c.username,
+		Password: c.password,
+	}
+
+	session, httpResp, err := c.RedfishAPI.SessionServiceCreateSession(ctx, sessionReq)
+	if screened := ScreenRedfishError(httpResp, err); screened != nil {
+		return screened
+	}
+
+	token := httpResp.Header.Get(headerAuthToken)
+	if token == "" {
+		return ErrRedfishMissingConfig{What: "X-Auth-Token in session response"}
+	}
+
+	c.RedfishCFG.DefaultHeader[headerAuthToken] = token
+	c.sessionURI = session.OdataId
+
+	return nil
+}
+
 // EjectVirtualMedia ejects a virtual media device attached to a host.
 func (c *Client) EjectVirtualMedia(ctx context.Context) error {
 	waitForEjectMedia := func(managerID string, mediaID string) error {
@@ -60,9 +149,16 @@ func (c *Client) EjectVirtualMedia(ctx context.Context) error {
 			totalRetries = systemActionRetries
 		}
 
-		for retry := 0; retry < totalRetries; retry++ {
-			vMediaMgr, httpResp, err := c.RedfishAPI.GetManagerVirtualMedia(ctx, managerID, mediaID)
-			if err = ScreenRedfishError(httpResp, err); err != nil {
+		backoff := retry.NewBackoff(pollBackoffBase, pollBackoffCap)
+		for attempt := 0; attempt < totalRetries; attempt++ {
+			var vMediaMgr redfishClient.VirtualMedia
+			err := c.doWithReauth(ctx, func() (*http.Response, error) {
+				var httpResp *http.Response
+				var err error
+				vMediaMgr, httpResp, err = c.RedfishAPI.GetManagerVirtualMedia(ctx, managerID, mediaID)
+				return httpResp, err
+			})
+			if err != nil {
 				return err
 			}
 
@@ -70,6 +166,10 @@ func (c *Client) EjectVirtualMedia(ctx context.Context) error {
 				log.Debugf("Successfully ejected virtual media.")
 				return nil
 			}
+
+			if err := backoff.Wait(ctx); err != nil {
+				return err
+			}
 		}
 
 		return ErrOperationRetriesExceeded{What: fmt.Sprintf("eject media %s", mediaID), Retries: totalRetries}
@@ -80,8 +180,13 @@ func (c *Client) EjectVirtualMedia(ctx context.Context) error {
 		return err
 	}
 
-	mediaCollection, httpResp, err := c.RedfishAPI.ListManagerVirtualMedia(ctx, managerID)
-	if err = ScreenRedfishError(httpResp, err); err != nil {
+	var mediaCollection redfishClient.Collection
+	err = c.doWithReauth(ctx, func() (*http.Response, error) {
+		var httpResp *http.Response
+		mediaCollection, httpResp, err = c.RedfishAPI.ListManagerVirtualMedia(ctx, managerID)
+		return httpResp, err
+	})
+	if err != nil {
 		return err
 	}
 
@@ -89,21 +194,30 @@ func (c *Client) EjectVirtualMedia(ctx context.Context) error {
 	for _, mediaURI := range mediaCollection.Members {
 		mediaID := GetResourceIDFromURL(mediaURI.OdataId)
 
-		vMediaMgr, httpResp, err := c.RedfishAPI.GetManagerVirtualMedia(ctx, managerID, mediaID)
-		if err = ScreenRedfishError(httpResp, err); err != nil {
+		var vMediaMgr redfishClient.VirtualMedia
+		err := c.doWithReauth(ctx, func() (*http.Response, error) {
+			var httpResp *http.Response
+			var err error
+			vMediaMgr, httpResp, err = c.RedfishAPI.GetManagerVirtualMedia(ctx, managerID, mediaID)
+			return httpResp, err
+		})
+		if err != nil {
 			return err
 		}
 
 		if *vMediaMgr.Inserted == true {
 			log.Debugf("'%s' has virtual media inserted. Attempting to eject.", vMediaMgr.Name)
 
-			var emptyBody map[string]interface{}
-			_, httpResp, err = c.RedfishAPI.EjectVirtualMedia(ctx, managerID, mediaID, emptyBody)
-			if err = ScreenRedfishError(httpResp, err); err != nil {
+			err := c.doWithReauth(ctx, func() (*http.Response, error) {
+				var emptyBody map[string]interface{}
+				_, httpResp, err := c.RedfishAPI.EjectVirtualMedia(ctx, managerID, mediaID, emptyBody)
+				return httpResp, err
+			})
+			if err != nil {
 				return err
 			}
 
-			if err = waitForEjectMedia(managerID, mediaID); err != nil {
+			if err := waitForEjectMedia(managerID, mediaID); err != nil {
 				return err
 			}
 		}
@@ -121,16 +235,25 @@ func (c *Client) RebootSystem(ctx context.Context) error {
 			totalRetries = systemActionRetries
 		}
 
-		for retry := 0; retry <= totalRetries; retry++ {
-			system, httpResp, err := c.RedfishAPI.GetSystem(ctx, c.nodeID)
-			if err = ScreenRedfishError(httpResp, err); err != nil {
+		backoff := retry.NewBackoff(pollBackoffBase, pollBackoffCap)
+		for attempt := 0; attempt <= totalRetries; attempt++ {
+			var system redfishClient.ComputerSystem
+			err := c.doWithReauth(ctx, func() (*http.Response, error) {
+				var httpResp *http.Response
+				var err error
+				system, httpResp, err = c.RedfishAPI.GetSystem(ctx, c.nodeID)
+				return httpResp, err
+			})
+			if err != nil {
 				return err
 			}
 			if system.PowerState == desiredState {
 				log.Debugf("Node '%s' reached power state '%s'.", c.nodeID, desiredState)
 				return nil
 			}
-			time.Sleep(systemRebootDelay)
+			if err := backoff.Wait(ctx); err != nil {
+				return err
+			}
 		}
 		return ErrOperationRetriesExceeded{
 			What:    fmt.Sprintf("reboot system %s", c.nodeID),
@@ -143,14 +266,17 @@ func (c *Client) RebootSystem(ctx context.Context) error {
 
 	// Send PowerOff request
 	resetReq.ResetType = redfishClient.RESETTYPE_FORCE_OFF
-	_, httpResp, err := c.RedfishAPI.ResetSystem(ctx, c.nodeID, resetReq)
-	if err = ScreenRedfishError(httpResp, err); err != nil {
+	err := c.doWithReauth(ctx, func() (*http.Response, error) {
+		_, httpResp, err := c.RedfishAPI.ResetSystem(ctx, c.nodeID, resetReq)
+		return httpResp, err
+	})
+	if err != nil {
 		log.Debugf("Failed to reboot node '%s': shutdown failure.", c.nodeID)
 		return err
 	}
 
 	// Check that node is powered off
-	if err = waitForPowerState(redfishClient.POWERSTATE_OFF); err != nil {
+	if err := waitForPowerState(redfishClient.POWERSTATE_OFF); err != nil {
 		return err
 	}
 
@@ -158,8 +284,11 @@ func (c *Client) RebootSystem(ctx context.Context) error {
 
 	// Send PowerOn request
 	resetReq.ResetType = redfishClient.RESETTYPE_ON
-	_, httpResp, err = c.RedfishAPI.ResetSystem(ctx, c.nodeID, resetReq)
-	if err = ScreenRedfishError(httpResp, err); err != nil {
+	err = c.doWithReauth(ctx, func() (*http.Response, error) {
+		_, httpResp, err := c.RedfishAPI.ResetSystem(ctx, c.nodeID, resetReq)
+		return httpResp, err
+	})
+	if err != nil {
 		log.Debugf("Failed to reboot node '%s': startup failure.", c.nodeID)
 		return err
 	}
@@ -179,7 +308,12 @@ func (c *Client) SetBootSourceByType(ctx context.Context) error {
 	log.Debugf("Setting boot device to '%s'.", vMediaType)
 
 	// Retrieve system information, containing available boot sources
-	system, _, err := c.RedfishAPI.GetSystem(ctx, c.nodeID)
+	var system redfishClient.ComputerSystem
+	err = c.doWithReauth(ctx, func() (*http.Response, error) {
+		var httpResp *http.Response
+		system, httpResp, err = c.RedfishAPI.GetSystem(ctx, c.nodeID)
+		return httpResp, err
+	})
 	if err != nil {
 		return ErrRedfishClient{Message: fmt.Sprintf("Get System[%s] failed with err: %v", c.nodeID, err)}
 	}
@@ -190,8 +324,11 @@ func (c *Client) SetBootSourceByType(ctx context.Context) error {
 			/* set boot source */
 			systemReq := redfishClient.ComputerSystem{}
 			systemReq.Boot.BootSourceOverrideTarget = bootSource
-			_, httpResp, err := c.RedfishAPI.SetSystem(ctx, c.nodeID, systemReq)
-			if err = ScreenRedfishError(httpResp, err); err != nil {
+			err := c.doWithReauth(ctx, func() (*http.Response, error) {
+				_, httpResp, err := c.RedfishAPI.SetSystem(ctx, c.nodeID, systemReq)
+				return httpResp, err
+			})
+			if err != nil {
 				return err
 			}
 
@@ -227,9 +364,11 @@ func (c *Client) SetVirtualMedia(ctx context.Context, isoPath string) error {
 	vMediaReq := redfishClient.InsertMediaRequestBody{}
 	vMediaReq.Image = isoPath
 	vMediaReq.Inserted = true
-	_, httpResp, err := c.RedfishAPI.InsertVirtualMedia(ctx, managerID, vMediaID, vMediaReq)
-
-	if err = ScreenRedfishError(httpResp, err); err != nil {
+	err = c.doWithReauth(ctx, func() (*http.Response, error) {
+		_, httpResp, err := c.RedfishAPI.InsertVirtualMedia(ctx, managerID, vMediaID, vMediaReq)
+		return httpResp, err
+	})
+	if err != nil {
 		return err
 	}
 
@@ -242,9 +381,10 @@ func (c *Client) SystemPowerOff(ctx context.Context) error {
 	resetReq := redfishClient.ResetRequestBody{}
 	resetReq.ResetType = redfishClient.RESETTYPE_FORCE_OFF
 
-	_, httpResp, err := c.RedfishAPI.ResetSystem(ctx, c.nodeID, resetReq)
-
-	return ScreenRedfishError(httpResp, err)
+	return c.doWithReauth(ctx, func() (*http.Response, error) {
+		_, httpResp, err := c.RedfishAPI.ResetSystem(ctx, c.nodeID, resetReq)
+		return httpResp, err
+	})
 }
 
 // SystemPowerOn powers on a host.
@@ -252,15 +392,22 @@ func (c *Client) SystemPowerOn(ctx context.Context) error {
 	resetReq := redfishClient.ResetRequestBody{}
 	resetReq.ResetType = redfishClient.RESETTYPE_ON
 
-	_, httpResp, err := c.RedfishAPI.ResetSystem(ctx, c.nodeID, resetReq)
-
-	return ScreenRedfishError(httpResp, err)
+	return c.doWithReauth(ctx, func() (*http.Response, error) {
+		_, httpResp, err := c.RedfishAPI.ResetSystem(ctx, c.nodeID, resetReq)
+		return httpResp, err
+	})
 }
 
 // SystemPowerStatus retrieves the power status of a host as a human-readable string.
 func (c *Client) SystemPowerStatus(ctx context.Context) (power.Status, error) {
-	computerSystem, httpResp, err := c.RedfishAPI.GetSystem(ctx, c.nodeID)
-	if err = ScreenRedfishError(httpResp, err); err != nil {
+	var computerSystem redfishClient.ComputerSystem
+	err := c.doWithReauth(ctx, func() (*http.Response, error) {
+		var httpResp *http.Response
+		var err error
+		computerSystem, httpResp, err = c.RedfishAPI.GetSystem(ctx, c.nodeID)
+		return httpResp, err
+	})
+	if err != nil {
 		return power.StatusUnknown, err
 	}
 
@@ -278,14 +425,152 @@ func (c *Client) SystemPowerStatus(ctx context.Context) (power.Status, error) {
 	}
 }
 
-// NewClient returns a client with the capability to make Redfish requests.
+// GetBIOSSettings retrieves the current BIOS/firmware attributes of the ephemeral node, e.g. boot
+// mode, SR-IOV, VT-d, and secure boot toggles.
+func (c *Client) GetBIOSSettings(ctx context.Context) (map[string]interface{}, error) {
+	var bios redfishClient.Bios
+	err := c.doWithReauth(ctx, func() (*http.Response, error) {
+		var httpResp *http.Response
+		var err error
+		bios, httpResp, err = c.RedfishAPI.GetBios(ctx, c.nodeID)
+		return httpResp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bios.Attributes, nil
+}
+
+// SetBIOSSettings stages pending BIOS attribute changes via the system's BIOS settings resource. Staged
+// attributes with ApplyTime "OnReset" take effect the next time the system is rebooted.
+func (c *Client) SetBIOSSettings(ctx context.Context, settings map[string]interface{}) error {
+	log.Debugf("Staging BIOS settings for node '%s'.", c.nodeID)
+
+	biosSettingsReq := redfishClient.Bios{}
+	biosSettingsReq.Attributes = settings
+
+	err := c.doWithReauth(ctx, func() (*http.Response, error) {
+		_, httpResp, err := c.RedfishAPI.SetBiosSettings(ctx, c.nodeID, biosSettingsReq)
+		return httpResp, err
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug("Successfully staged BIOS settings.")
+	return nil
+}
+
+// ApplyBIOSSettingsAndReboot stages the given BIOS attributes, reboots the host so that the BMC can
+// apply them, and polls the BIOS settings resource until the pending attributes have been applied.
+// Any per-attribute validation errors surfaced by the BMC are returned as ErrBIOSSettingsApplyFailed.
+func (c *Client) ApplyBIOSSettingsAndReboot(ctx context.Context, settings map[string]interface{}) error {
+	if err := c.SetBIOSSettings(ctx, settings); err != nil {
+		return err
+	}
+
+	log.Debugf("Rebooting node '%s' to apply staged BIOS settings.", c.nodeID)
+	if err := c.RebootSystem(ctx); err != nil {
+		return err
+	}
+
+	return c.waitForBIOSSettingsApplied(ctx, settings)
+}
+
+// messageSeverityCritical is the Redfish Message.Severity value that indicates an actual error, as
+// opposed to the informational or warning messages a BMC may also attach to a settings resource.
+const messageSeverityCritical = "Critical"
+
+// waitForBIOSSettingsApplied polls the pending BIOS settings resource for validation errors and the
+// live BIOS resource for the applied attributes, until the desired attributes are in effect, the BMC
+// reports a validation error for them, or the retry budget is exhausted. The pending resource is
+// polled separately from the live one because BMCs commonly reset or clear the pending Settings
+// resource once a staged apply completes, so its Attributes can't be relied on to detect success.
+func (c *Client) waitForBIOSSettingsApplied(ctx context.Context, settings map[string]interface{}) error {
+	totalRetries, ok := ctx.Value(ctxKeyNumRetries).(int)
+	if !ok {
+		totalRetries = biosSettingsApplyRetries
+	}
+
+	backoff := retry.NewBackoff(pollBackoffBase, pollBackoffCap)
+	for attempt := 0; attempt < totalRetries; attempt++ {
+		var pending redfishClient.Bios
+		err := c.doWithReauth(ctx, func() (*http.Response, error) {
+			var httpResp *http.Response
+			var err error
+			pending, httpResp, err = c.RedfishAPI.GetBiosSettings(ctx, c.nodeID)
+			return httpResp, err
+		})
+		if err != nil {
+			return err
+		}
+
+		if failures := criticalMessages(pending.Messages); len(failures) > 0 {
+			return ErrBIOSSettingsApplyFailed{NodeID: c.nodeID, Messages: failures}
+		}
+
+		applied, err := c.GetBIOSSettings(ctx)
+		if err != nil {
+			return err
+		}
+
+		if biosSettingsApplied(settings, applied) {
+			log.Debugf("Successfully applied BIOS settings on node '%s'.", c.nodeID)
+			return nil
+		}
+
+		if err := backoff.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	return ErrOperationRetriesExceeded{
+		What:    fmt.Sprintf("apply BIOS settings on %s", c.nodeID),
+		Retries: totalRetries,
+	}
+}
+
+// criticalMessages filters messages down to those with Severity "Critical", i.e. actual validation
+// errors, ignoring the informational or warning messages a BMC may also report.
+func criticalMessages(messages []redfishClient.Message) []redfishClient.Message {
+	var failures []redfishClient.Message
+	for _, message := range messages {
+		if message.Severity == messageSeverityCritical {
+			failures = append(failures, message)
+		}
+	}
+
+	return failures
+}
+
+// biosSettingsApplied returns true if every desired attribute is already reflected in the reported
+// attributes, meaning the BMC has finished reconciling the pending BIOS settings.
+func biosSettingsApplied(desired, reported map[string]interface{}) bool {
+	for attribute, value := range desired {
+		reportedValue, ok := reported[attribute]
+		if !ok || reportedValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewClient returns a client with the capability to make Redfish requests. authMode selects how the
+// client authenticates; an empty authMode defaults to AuthModeBasic.
 func NewClient(redfishURL string,
 	insecure bool,
 	useProxy bool,
+	authMode AuthMode,
 	username string,
 	password string) (context.Context, *Client, error) {
+	if authMode == "" {
+		authMode = AuthModeBasic
+	}
+
 	var ctx context.Context
-	if username != "" && password != "" {
+	if authMode == AuthModeBasic && username != "" && password != "" {
 		ctx = context.WithValue(
 			context.Background(),
 			redfishClient.ContextBasicAuth,
@@ -341,6 +626,15 @@ func NewClient(redfishURL string,
 		nodeID:     systemID,
 		RedfishAPI: redfishClient.NewAPIClient(cfg).DefaultApi,
 		RedfishCFG: cfg,
+		authMode:   authMode,
+		username:   username,
+		password:   password,
+	}
+
+	if authMode == AuthModeSession {
+		if err := c.createSession(ctx); err != nil {
+			return ctx, nil, err
+		}
 	}
 
 	return ctx, c, nil
@@ -0,0 +1,333 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	redfishAPI "opendev.org/airship/go-redfish/api"
+	redfishClient "opendev.org/airship/go-redfish/client"
+)
+
+// fakeRedfishAPI is a redfishAPI.RedfishAPI backed by test-supplied function fields, standing in
+// for a real BMC. It embeds the interface so that any method a test doesn't override panics if
+// called, rather than requiring every one of the generated client's endpoints to be stubbed out.
+type fakeRedfishAPI struct {
+	redfishAPI.RedfishAPI
+
+	getBios                     func(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error)
+	getBiosSettings             func(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error)
+	setBiosSettings             func(ctx context.Context, systemID string, body redfishClient.Bios) (redfishClient.Bios, *http.Response, error)
+	getSystem                   func(ctx context.Context, systemID string) (redfishClient.ComputerSystem, *http.Response, error)
+	resetSystem                 func(ctx context.Context, systemID string, body redfishClient.ResetRequestBody) (redfishClient.ComputerSystem, *http.Response, error)
+	deleteSession               func(ctx context.Context, sessionID string) (map[string]interface{}, *http.Response, error)
+	sessionServiceCreateSession func(ctx context.Context, body redfishClient.SessionCreateRequestBody) (redfishClient.Session, *http.Response, error)
+}
+
+func (f *fakeRedfishAPI) GetBios(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error) {
+	return f.getBios(ctx, systemID)
+}
+
+func (f *fakeRedfishAPI) GetBiosSettings(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error) {
+	return f.getBiosSettings(ctx, systemID)
+}
+
+func (f *fakeRedfishAPI) SetBiosSettings(
+	ctx context.Context, systemID string, body redfishClient.Bios,
+) (redfishClient.Bios, *http.Response, error) {
+	return f.setBiosSettings(ctx, systemID, body)
+}
+
+func (f *fakeRedfishAPI) GetSystem(ctx context.Context, systemID string) (redfishClient.ComputerSystem, *http.Response, error) {
+	return f.getSystem(ctx, systemID)
+}
+
+func (f *fakeRedfishAPI) ResetSystem(
+	ctx context.Context, systemID string, body redfishClient.ResetRequestBody,
+) (redfishClient.ComputerSystem, *http.Response, error) {
+	return f.resetSystem(ctx, systemID, body)
+}
+
+func (f *fakeRedfishAPI) DeleteSession(ctx context.Context, sessionID string) (map[string]interface{}, *http.Response, error) {
+	return f.deleteSession(ctx, sessionID)
+}
+
+func (f *fakeRedfishAPI) SessionServiceCreateSession(
+	ctx context.Context, body redfishClient.SessionCreateRequestBody,
+) (redfishClient.Session, *http.Response, error) {
+	return f.sessionServiceCreateSession(ctx, body)
+}
+
+// fakeOKResponse returns a bare 200 response with an initialized header, suitable for call sites
+// that inspect response headers, e.g. doWithReauth's 401 check and createSession's token lookup.
+func fakeOKResponse() *http.Response {
+	return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header)}
+}
+
+func fakeUnauthorizedResponse() *http.Response {
+	return &http.Response{StatusCode: http.StatusUnauthorized, Header: make(http.Header)}
+}
+
+// newTestClient returns a Client wired to api, with HTTP Basic auth and a nodeID of
+// "System.Embedded.1", suitable as a starting point for tests that need a different authMode or
+// credentials.
+func newTestClient(api redfishAPI.RedfishAPI) *Client {
+	return &Client{
+		nodeID:     "System.Embedded.1",
+		RedfishAPI: api,
+		RedfishCFG: &redfishClient.Configuration{DefaultHeader: make(map[string]string)},
+		authMode:   AuthModeBasic,
+	}
+}
+
+func TestClientGetBIOSSettings(t *testing.T) {
+	api := &fakeRedfishAPI{
+		getBios: func(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error) {
+			return redfishClient.Bios{Attributes: map[string]interface{}{"BootMode": "Uefi"}}, fakeOKResponse(), nil
+		},
+	}
+	client := newTestClient(api)
+
+	attrs, err := client.GetBIOSSettings(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"BootMode": "Uefi"}, attrs)
+}
+
+func TestClientGetBIOSSettingsError(t *testing.T) {
+	api := &fakeRedfishAPI{
+		getBios: func(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error) {
+			return redfishClient.Bios{}, nil, errors.New("bmc unreachable")
+		},
+	}
+	client := newTestClient(api)
+
+	_, err := client.GetBIOSSettings(context.Background())
+
+	require.Error(t, err)
+}
+
+func TestClientSetBIOSSettings(t *testing.T) {
+	var gotSystemID string
+	var gotSettings redfishClient.Bios
+	api := &fakeRedfishAPI{
+		setBiosSettings: func(
+			ctx context.Context, systemID string, body redfishClient.Bios,
+		) (redfishClient.Bios, *http.Response, error) {
+			gotSystemID = systemID
+			gotSettings = body
+			return redfishClient.Bios{}, fakeOKResponse(), nil
+		},
+	}
+	client := newTestClient(api)
+	settings := map[string]interface{}{"BootMode": "Uefi"}
+
+	err := client.SetBIOSSettings(context.Background(), settings)
+
+	require.NoError(t, err)
+	assert.Equal(t, "System.Embedded.1", gotSystemID)
+	assert.Equal(t, settings, gotSettings.Attributes)
+}
+
+func TestWaitForBIOSSettingsAppliedCriticalMessage(t *testing.T) {
+	api := &fakeRedfishAPI{
+		getBiosSettings: func(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error) {
+			return redfishClient.Bios{
+				Messages: []redfishClient.Message{{Severity: messageSeverityCritical, Message: "invalid value"}},
+			}, fakeOKResponse(), nil
+		},
+	}
+	client := newTestClient(api)
+
+	err := client.waitForBIOSSettingsApplied(context.Background(), map[string]interface{}{"BootMode": "Uefi"})
+
+	require.Error(t, err)
+	assert.IsType(t, ErrBIOSSettingsApplyFailed{}, err)
+}
+
+func TestWaitForBIOSSettingsAppliedIgnoresInformationalMessages(t *testing.T) {
+	api := &fakeRedfishAPI{
+		getBiosSettings: func(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error) {
+			return redfishClient.Bios{
+				Messages: []redfishClient.Message{{Severity: "OK", Message: "staged"}},
+			}, fakeOKResponse(), nil
+		},
+		getBios: func(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error) {
+			return redfishClient.Bios{Attributes: map[string]interface{}{"BootMode": "Uefi"}}, fakeOKResponse(), nil
+		},
+	}
+	client := newTestClient(api)
+
+	err := client.waitForBIOSSettingsApplied(context.Background(), map[string]interface{}{"BootMode": "Uefi"})
+
+	require.NoError(t, err)
+}
+
+func TestWaitForBIOSSettingsAppliedHonorsContextCancellation(t *testing.T) {
+	api := &fakeRedfishAPI{
+		getBiosSettings: func(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error) {
+			return redfishClient.Bios{}, fakeOKResponse(), nil
+		},
+		getBios: func(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error) {
+			// Never matches the desired settings, forcing the poll loop to back off and
+			// observe the cancellation instead of looping until retries are exhausted.
+			return redfishClient.Bios{Attributes: map[string]interface{}{}}, fakeOKResponse(), nil
+		},
+	}
+	client := newTestClient(api)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.waitForBIOSSettingsApplied(ctx, map[string]interface{}{"BootMode": "Uefi"})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// fakeBIOSState tracks the in-memory state a real BMC would hold across the ResetSystem/GetSystem
+// and SetBiosSettings/GetBios calls ApplyBIOSSettingsAndReboot makes, so the fake can simulate the
+// BMC applying staged attributes once the host is power-cycled.
+type fakeBIOSState struct {
+	power   redfishClient.PowerState
+	staged  map[string]interface{}
+	applied map[string]interface{}
+}
+
+func TestClientApplyBIOSSettingsAndReboot(t *testing.T) {
+	state := &fakeBIOSState{power: redfishClient.POWERSTATE_ON}
+	api := &fakeRedfishAPI{
+		setBiosSettings: func(
+			ctx context.Context, systemID string, body redfishClient.Bios,
+		) (redfishClient.Bios, *http.Response, error) {
+			state.staged = body.Attributes
+			return redfishClient.Bios{}, fakeOKResponse(), nil
+		},
+		resetSystem: func(
+			ctx context.Context, systemID string, body redfishClient.ResetRequestBody,
+		) (redfishClient.ComputerSystem, *http.Response, error) {
+			switch body.ResetType {
+			case redfishClient.RESETTYPE_FORCE_OFF:
+				state.power = redfishClient.POWERSTATE_OFF
+			case redfishClient.RESETTYPE_ON:
+				state.power = redfishClient.POWERSTATE_ON
+				state.applied = state.staged
+			}
+			return redfishClient.ComputerSystem{}, fakeOKResponse(), nil
+		},
+		getSystem: func(ctx context.Context, systemID string) (redfishClient.ComputerSystem, *http.Response, error) {
+			return redfishClient.ComputerSystem{PowerState: state.power}, fakeOKResponse(), nil
+		},
+		getBiosSettings: func(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error) {
+			return redfishClient.Bios{}, fakeOKResponse(), nil
+		},
+		getBios: func(ctx context.Context, systemID string) (redfishClient.Bios, *http.Response, error) {
+			return redfishClient.Bios{Attributes: state.applied}, fakeOKResponse(), nil
+		},
+	}
+	client := newTestClient(api)
+	settings := map[string]interface{}{"BootMode": "Uefi"}
+
+	err := client.ApplyBIOSSettingsAndReboot(context.Background(), settings)
+
+	require.NoError(t, err)
+	assert.Equal(t, settings, state.applied)
+}
+
+func TestClientCreateSessionMissingToken(t *testing.T) {
+	api := &fakeRedfishAPI{
+		sessionServiceCreateSession: func(
+			ctx context.Context, body redfishClient.SessionCreateRequestBody,
+		) (redfishClient.Session, *http.Response, error) {
+			return redfishClient.Session{}, fakeOKResponse(), nil
+		},
+	}
+	client := newTestClient(api)
+	client.authMode = AuthModeSession
+
+	err := client.createSession(context.Background())
+
+	assert.IsType(t, ErrRedfishMissingConfig{}, err)
+}
+
+func TestClientSystemPowerOnReauthenticatesOnExpiredSession(t *testing.T) {
+	var resetCalls, createSessionCalls int
+	api := &fakeRedfishAPI{
+		resetSystem: func(
+			ctx context.Context, systemID string, body redfishClient.ResetRequestBody,
+		) (redfishClient.ComputerSystem, *http.Response, error) {
+			resetCalls++
+			if resetCalls == 1 {
+				return redfishClient.ComputerSystem{}, fakeUnauthorizedResponse(), errors.New("session expired")
+			}
+			return redfishClient.ComputerSystem{}, fakeOKResponse(), nil
+		},
+		sessionServiceCreateSession: func(
+			ctx context.Context, body redfishClient.SessionCreateRequestBody,
+		) (redfishClient.Session, *http.Response, error) {
+			createSessionCalls++
+			resp := fakeOKResponse()
+			resp.Header.Set(headerAuthToken, "new-token")
+			return redfishClient.Session{OdataId: "/redfish/v1/SessionService/Sessions/2"}, resp, nil
+		},
+	}
+	client := newTestClient(api)
+	client.authMode = AuthModeSession
+	client.username, client.password = "user", "pass"
+
+	err := client.SystemPowerOn(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, resetCalls)
+	assert.Equal(t, 1, createSessionCalls)
+	assert.Equal(t, "new-token", client.RedfishCFG.DefaultHeader[headerAuthToken])
+	assert.Equal(t, "/redfish/v1/SessionService/Sessions/2", client.sessionURI)
+}
+
+func TestClientCloseLogsOutSessionAuth(t *testing.T) {
+	var deleteSessionCalls int
+	api := &fakeRedfishAPI{
+		deleteSession: func(ctx context.Context, sessionID string) (map[string]interface{}, *http.Response, error) {
+			deleteSessionCalls++
+			return nil, fakeOKResponse(), nil
+		},
+	}
+	client := newTestClient(api)
+	client.authMode = AuthModeSession
+	client.sessionURI = "/redfish/v1/SessionService/Sessions/2"
+
+	err := client.Close(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleteSessionCalls)
+}
+
+func TestClientCloseNoOpForBasicAuth(t *testing.T) {
+	var deleteSessionCalls int
+	api := &fakeRedfishAPI{
+		deleteSession: func(ctx context.Context, sessionID string) (map[string]interface{}, *http.Response, error) {
+			deleteSessionCalls++
+			return nil, fakeOKResponse(), nil
+		},
+	}
+	client := newTestClient(api)
+
+	err := client.Close(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleteSessionCalls)
+}
@@ -0,0 +1,30 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"fmt"
+
+	redfishClient "opendev.org/airship/go-redfish/client"
+)
+
+// ErrBIOSSettingsApplyFailed is returned when the BMC reports validation messages against one or more
+// pending BIOS attributes instead of applying them.
+type ErrBIOSSettingsApplyFailed struct {
+	NodeID   string
+	Messages []redfishClient.Message
+}
+
+func (e ErrBIOSSettingsApplyFailed) Error() string {
+	return fmt.Sprintf("failed to apply BIOS settings on node '%s': %v", e.NodeID, e.Messages)
+}
@@ -0,0 +1,62 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retry provides a small exponential backoff helper shared by the out-of-band management
+// backends and the Fleet type, so that polling loops against remote BMCs back off instead of
+// hammering them and return promptly when their context is canceled.
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff computes successive exponential backoff delays with jitter, doubling the delay on every
+// call to Wait up to Cap. A zero-value Backoff is not usable; construct one with NewBackoff.
+type Backoff struct {
+	Base time.Duration
+	Cap  time.Duration
+
+	attempt int
+}
+
+// NewBackoff returns a Backoff starting at base and never waiting longer than cap.
+func NewBackoff(base, cap time.Duration) *Backoff {
+	return &Backoff{Base: base, Cap: cap}
+}
+
+// Wait blocks until the next backoff interval elapses or ctx is done, whichever happens first. It
+// returns ctx.Err() if the context is canceled before the interval elapses.
+func (b *Backoff) Wait(ctx context.Context) error {
+	timer := time.NewTimer(b.next())
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// next returns the delay for the upcoming attempt and advances the attempt counter.
+func (b *Backoff) next() time.Duration {
+	exp := math.Min(float64(b.Cap), float64(b.Base)*math.Pow(2, float64(b.attempt)))
+	b.attempt++
+
+	// Equal jitter: pick uniformly between half of the exponential delay and the full delay so
+	// that retrying callers don't all wake up in lockstep.
+	half := exp / 2
+	return time.Duration(half + rand.Float64()*half) //nolint:gosec
+}